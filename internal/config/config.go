@@ -1,136 +1,176 @@
 package config
 
 import (
+	"database/sql"
 	"encoding/xml"
-	"fmt"
-	"github.com/joho/godotenv"
-	"io"
 	"os"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var (
-	cfg  *APIConfig
-	once sync.Once
+	current  atomic.Pointer[APIConfig]
+	loadOnce sync.Once
+	// activeLoader is the Loader LoadConfig built, kept around so Reload
+	// can re-run the exact same layered load instead of just re-reading
+	// the config file.
+	activeLoader *Loader
 )
 
 // APIConfig represents the root element.
 type APIConfig struct {
 	XMLName        xml.Name             `xml:"API"`
-	RequestDump    bool                 `xml:"REQUEST_DUMP,attr"`
-	Context        ContextConfig        `xml:"CONTEXT"`
-	Authentication AuthenticationConfig `xml:"AUTHENTICATION"`
-	Pagination     PaginationConfig     `xml:"PAGINATION"`
-	DB             DBConfig             `xml:"DB"`
-	ThirdParty     ThirdPartyConfig     `xml:"THIRD_PARTY"`
+	RequestDump    bool                 `xml:"REQUEST_DUMP,attr" yaml:"REQUEST_DUMP" json:"REQUEST_DUMP"`
+	Context        ContextConfig        `xml:"CONTEXT" yaml:"CONTEXT" json:"CONTEXT"`
+	Authentication AuthenticationConfig `xml:"AUTHENTICATION" yaml:"AUTHENTICATION" json:"AUTHENTICATION"`
+	Pagination     PaginationConfig     `xml:"PAGINATION" yaml:"PAGINATION" json:"PAGINATION"`
+	// DBs holds one entry per <DB NAME="..."> element, e.g. a separate
+	// database each for content, auth, and analytics. Look them up by name
+	// with the DB method or the package-level DB func, not by indexing DBs
+	// directly.
+	DBs        []DBConfig       `xml:"DB" yaml:"DB" json:"DB"`
+	ThirdParty ThirdPartyConfig `xml:"THIRD_PARTY" yaml:"THIRD_PARTY" json:"THIRD_PARTY"`
+
+	dbIndex       map[string]DBConfig
+	providerIndex map[string]ProviderConfig
 }
 
 // ContextConfig holds basic server settings.
 type ContextConfig struct {
-	Port            int                  `xml:"PORT"`
-	Host            string               `xml:"HOST"`
-	Path            string               `xml:"PATH"`
-	TimeZone        string               `xml:"TIME_ZONE"`
-	EnableBasicAuth bool                 `xml:"ENABLE_BASIC_AUTH"`
-	Mode            string               `xml:"MODE"` // "release" or "debug"
-	TrustedProxies  TrustedProxiesConfig `xml:"TRUSTED_PROXIES"`
+	Port            int                  `xml:"PORT" yaml:"PORT" json:"PORT"`
+	Host            string               `xml:"HOST" yaml:"HOST" json:"HOST"`
+	Path            string               `xml:"PATH" yaml:"PATH" json:"PATH"`
+	TimeZone        string               `xml:"TIME_ZONE" yaml:"TIME_ZONE" json:"TIME_ZONE"`
+	EnableBasicAuth bool                 `xml:"ENABLE_BASIC_AUTH" yaml:"ENABLE_BASIC_AUTH" json:"ENABLE_BASIC_AUTH"`
+	Mode            string               `xml:"MODE" yaml:"MODE" json:"MODE" default:"release"` // "release" or "debug"
+	TrustedProxies  TrustedProxiesConfig `xml:"TRUSTED_PROXIES" yaml:"TRUSTED_PROXIES" json:"TRUSTED_PROXIES"`
 }
 
 // TrustedProxiesConfig holds a list of trusted proxy IP addresses.
 type TrustedProxiesConfig struct {
-	Proxies []string `xml:"PROXY"`
+	Proxies []string `xml:"PROXY" yaml:"PROXY" json:"PROXY"`
 }
 
+// ThirdPartyConfig lists the LLM backends this service can call.
 type ThirdPartyConfig struct {
-	HFToken    string `xml:"HF_TOKEN"`
-	OllamaHost string `xml:"OLLAMA_HOST"`
+	Providers []ProviderConfig `xml:"PROVIDER" yaml:"PROVIDERS" json:"PROVIDERS"`
+
+	// Deprecated: HFToken and OllamaHost are the pre-chunk0-6 fields this
+	// list replaced. They're only parsed so an existing config file keeps
+	// working for one release; see migrateLegacyProviders. Set PROVIDER
+	// entries instead.
+	HFToken    string `xml:"HF_TOKEN" yaml:"HF_TOKEN" json:"HF_TOKEN"`
+	OllamaHost string `xml:"OLLAMA_HOST" yaml:"OLLAMA_HOST" json:"OLLAMA_HOST"`
 }
 
 // AuthenticationConfig holds authentication settings.
 type AuthenticationConfig struct {
-	MultipleSameUserSessions bool `xml:"MULTIPLE_SAME_USER_SESSIONS,attr"`
-	EnableTokenAuth          bool `xml:"ENABLE_TOKEN_AUTH"`
-	SessionTimeout           int  `xml:"SESSION_TIMEOUT"`
+	MultipleSameUserSessions bool     `xml:"MULTIPLE_SAME_USER_SESSIONS,attr" yaml:"MULTIPLE_SAME_USER_SESSIONS" json:"MULTIPLE_SAME_USER_SESSIONS"`
+	EnableTokenAuth          bool     `xml:"ENABLE_TOKEN_AUTH" yaml:"ENABLE_TOKEN_AUTH" json:"ENABLE_TOKEN_AUTH"`
+	SessionTimeout           Duration `xml:"SESSION_TIMEOUT" yaml:"SESSION_TIMEOUT" json:"SESSION_TIMEOUT" default:"30m"`
 }
 
 // PaginationConfig holds pagination settings.
 type PaginationConfig struct {
-	PageSize int `xml:"PAGE_SIZE"`
+	PageSize int `xml:"PAGE_SIZE" yaml:"PAGE_SIZE" json:"PAGE_SIZE" default:"20"`
 }
 
-// DBConfig holds database connection settings.
+// DBConfig holds the connection settings for one named database, e.g.
+// <DB NAME="inkwell">...</DB>. Use APIConfig.DB(name) to look one up.
 type DBConfig struct {
-	Initialize bool         `xml:"INITIALIZE"`
-	Server     string       `xml:"SERVER"`
-	Host       string       `xml:"HOST"`
-	Port       int          `xml:"PORT"`
-	Driver     string       `xml:"DRIVER"`
-	SSLMode    string       `xml:"SSL_MODE"`
-	Names      DBNames      `xml:"NAMES"`
-	Username   string       `xml:"USERNAME"`
-	Password   DBPassword   `xml:"PASSWORD"`
-	Pool       DBPoolConfig `xml:"POOL"`
+	Name       string       `xml:"NAME,attr" yaml:"NAME" json:"NAME"`
+	Initialize bool         `xml:"INITIALIZE" yaml:"INITIALIZE" json:"INITIALIZE"`
+	Server     string       `xml:"SERVER" yaml:"SERVER" json:"SERVER"`
+	Host       string       `xml:"HOST" yaml:"HOST" json:"HOST"`
+	Port       int          `xml:"PORT" yaml:"PORT" json:"PORT"`
+	Driver     string       `xml:"DRIVER" yaml:"DRIVER" json:"DRIVER"`
+	SSLMode    string       `xml:"SSL_MODE" yaml:"SSL_MODE" json:"SSL_MODE" default:"disable"`
+	Names      DBNames      `xml:"NAMES" yaml:"NAMES" json:"NAMES"`
+	Username   string       `xml:"USERNAME" yaml:"USERNAME" json:"USERNAME"`
+	Password   DBPassword   `xml:"PASSWORD" yaml:"PASSWORD" json:"PASSWORD"`
+	Pool       DBPoolConfig `xml:"POOL" yaml:"POOL" json:"POOL"`
+	// DSN, if set, overrides Host/Port/... with a driver-specific
+	// connection string. Also used internally to carry a chosen replica's
+	// DSN; see Replica.
+	DSN string `xml:"DSN" yaml:"DSN" json:"DSN"`
+	// Replicas lists read-replica DSNs this DB can route reads to.
+	Replicas ReplicasConfig `xml:"REPLICAS" yaml:"REPLICAS" json:"REPLICAS"`
 }
 
 // DBNames holds the names defined in the DB section.
 type DBNames struct {
-	INKWELL string `xml:"INKWELL,attr"`
+	INKWELL string `xml:"INKWELL,attr" yaml:"INKWELL" json:"INKWELL"`
 }
 
-// DBPassword holds password details.
+// DBPassword holds password details. Type selects how Value is
+// interpreted: "plain" (default) uses Value as-is, "env" reads the env var
+// named by Value, "file" reads the trimmed contents of the file at Value,
+// and "vault" resolves Value as a "path#field" Vault KV reference. See
+// RegisterSecretResolver to add other types.
 type DBPassword struct {
-	Type  string `xml:"TYPE,attr"`
-	Value string `xml:",chardata"`
+	Type  string `xml:"TYPE,attr" yaml:"TYPE" json:"TYPE"`
+	Value string `xml:",chardata" yaml:"VALUE" json:"VALUE"`
 }
 
 // DBPoolConfig holds database connection pooling settings.
 type DBPoolConfig struct {
-	MaxOpenConns    int `xml:"MAX_OPEN_CONNS"`
-	MaxIdleConns    int `xml:"MAX_IDLE_CONNS"`
-	ConnMaxLifetime int `xml:"CONN_MAX_LIFETIME"`
+	MaxOpenConns      int      `xml:"MAX_OPEN_CONNS" yaml:"MAX_OPEN_CONNS" json:"MAX_OPEN_CONNS" default:"25"`
+	MaxIdleConns      int      `xml:"MAX_IDLE_CONNS" yaml:"MAX_IDLE_CONNS" json:"MAX_IDLE_CONNS" default:"5"`
+	ConnMaxLifetime   Duration `xml:"CONN_MAX_LIFETIME" yaml:"CONN_MAX_LIFETIME" json:"CONN_MAX_LIFETIME" default:"30m"`
+	MaxIdleTime       Duration `xml:"MAX_IDLE_TIME" yaml:"MAX_IDLE_TIME" json:"MAX_IDLE_TIME" default:"5m"`
+	HealthCheckPeriod Duration `xml:"HEALTH_CHECK_PERIOD" yaml:"HEALTH_CHECK_PERIOD" json:"HEALTH_CHECK_PERIOD" default:"1m"`
+	ConnectTimeout    Duration `xml:"CONNECT_TIMEOUT" yaml:"CONNECT_TIMEOUT" json:"CONNECT_TIMEOUT" default:"5s"`
 }
 
-// LoadConfig loads and parses the XML configuration from the given file.
-func LoadConfig(xmlPath string) (*APIConfig, error) {
-	once.Do(func() {
-		f, err := os.Open(xmlPath)
-		if err == nil {
-			defer f.Close()
-
-			data, err := io.ReadAll(f)
-			if err == nil {
-				var newCfg APIConfig
-				if err := xml.Unmarshal(data, &newCfg); err == nil {
-					cfg = &newCfg
-					return
-				}
-			}
-		}
-
-		// If XML file is not found, try loading from .env
-		fmt.Println("Config file not found, attempting to load from environment...")
+// Configure applies p to db: max open/idle connections and the two
+// lifetime limits. HealthCheckPeriod and ConnectTimeout are not sql.DB
+// settings; Configure does not apply them, and nothing in this repository
+// reads them.
+func (p DBPoolConfig) Configure(db *sql.DB) {
+	db.SetMaxOpenConns(p.MaxOpenConns)
+	db.SetMaxIdleConns(p.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(p.ConnMaxLifetime))
+	db.SetConnMaxIdleTime(time.Duration(p.MaxIdleTime))
+}
 
-		_ = godotenv.Load() // Load .env file if present
-		xmlConfig := os.Getenv("CONFIG_XML")
+// LoadConfig loads the configuration for xmlPath through the default
+// Loader (built-in defaults -> config.default.xml -> xmlPath/$CONFIG_XML ->
+// .env/process env -> flags), then starts watching xmlPath for changes so
+// the process can pick up edits without a restart. See Subscribe and
+// Snapshot. For more control over the layers, build a Loader directly.
+func LoadConfig(xmlPath string) (*APIConfig, error) {
+	loadOnce.Do(func() {
+		loader := NewLoader()
+		loader.ConfigPath = xmlPath
 
-		if xmlConfig == "" {
-			fmt.Println("No XML configuration found in environment variables")
+		newCfg, err := loader.Load()
+		if err != nil {
 			return
 		}
 
-		var newCfg APIConfig
-		if err := xml.Unmarshal([]byte(xmlConfig), &newCfg); err == nil {
-			cfg = &newCfg
-		}
+		current.Store(newCfg)
+		activeLoader = loader
+		startWatch(xmlPath)
 	})
 
-	if cfg == nil {
-		return nil, os.ErrInvalid
+	if cfg := current.Load(); cfg != nil {
+		return cfg, nil
 	}
-	return cfg, nil
+	return nil, os.ErrInvalid
 }
 
-// GetConfig returns the loaded configuration.
+// GetConfig returns the currently active configuration.
 func GetConfig() *APIConfig {
-	return cfg
+	return current.Load()
+}
+
+// Snapshot returns an immutable copy of the currently active configuration.
+// Callers that need to read several fields together without racing a
+// concurrent reload should use Snapshot instead of GetConfig.
+func Snapshot() APIConfig {
+	if cfg := current.Load(); cfg != nil {
+		return *cfg
+	}
+	return APIConfig{}
 }
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"reflect"
+	"strconv"
+)
+
+var durationType = reflect.TypeOf(Duration(0))
+
+// applyDefaults walks cfg and fills any zero-valued field that carries a
+// `default:"..."` struct tag, so a partial config file (missing PAGE_SIZE,
+// POOL.MAX_OPEN_CONNS, ...) still ends up with sane values.
+func applyDefaults(cfg *APIConfig) {
+	applyDefaultsValue(reflect.ValueOf(cfg).Elem())
+}
+
+func applyDefaultsValue(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fv := v.Field(i)
+		if !fv.CanSet() {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+			applyDefaultsValue(fv)
+			continue
+		}
+		if field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Struct {
+			for i := 0; i < fv.Len(); i++ {
+				applyDefaultsValue(fv.Index(i))
+			}
+			continue
+		}
+
+		tag, ok := field.Tag.Lookup("default")
+		if !ok || !fv.IsZero() {
+			continue
+		}
+		setDefault(fv, tag)
+	}
+}
+
+func setDefault(fv reflect.Value, tag string) {
+	if fv.Type() == durationType {
+		if d, err := ParseDuration(tag); err == nil {
+			fv.Set(reflect.ValueOf(d))
+		}
+		return
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(tag)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, err := strconv.ParseInt(tag, 10, 64); err == nil {
+			fv.SetInt(n)
+		}
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(tag); err == nil {
+			fv.SetBool(b)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApplyDefaultsFillsZeroFields(t *testing.T) {
+	cfg := &APIConfig{
+		DBs: []DBConfig{{Name: "inkwell"}},
+	}
+
+	applyDefaults(cfg)
+
+	if cfg.Pagination.PageSize != 20 {
+		t.Errorf("Pagination.PageSize = %d, want 20", cfg.Pagination.PageSize)
+	}
+	if cfg.DBs[0].Pool.MaxOpenConns != 25 {
+		t.Errorf("DBs[0].Pool.MaxOpenConns = %d, want 25", cfg.DBs[0].Pool.MaxOpenConns)
+	}
+	if cfg.DBs[0].SSLMode != "disable" {
+		t.Errorf("DBs[0].SSLMode = %q, want %q", cfg.DBs[0].SSLMode, "disable")
+	}
+	if cfg.Authentication.SessionTimeout != Duration(30*time.Minute) {
+		t.Errorf("Authentication.SessionTimeout = %v, want 30m", cfg.Authentication.SessionTimeout)
+	}
+}
+
+func TestApplyDefaultsLeavesNonZeroFieldsAlone(t *testing.T) {
+	cfg := &APIConfig{
+		Pagination: PaginationConfig{PageSize: 50},
+		DBs:        []DBConfig{{Name: "inkwell", Pool: DBPoolConfig{MaxOpenConns: 5}}},
+	}
+
+	applyDefaults(cfg)
+
+	if cfg.Pagination.PageSize != 50 {
+		t.Errorf("Pagination.PageSize = %d, want unchanged 50", cfg.Pagination.PageSize)
+	}
+	if cfg.DBs[0].Pool.MaxOpenConns != 5 {
+		t.Errorf("DBs[0].Pool.MaxOpenConns = %d, want unchanged 5", cfg.DBs[0].Pool.MaxOpenConns)
+	}
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Duration is a time.Duration that unmarshals from XML/YAML/JSON either as
+// a Go duration string ("30s", "5m") or, for backward compatibility with
+// the old *_SECONDS int fields, a bare integer number of seconds.
+type Duration time.Duration
+
+// String implements fmt.Stringer.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// ParseDuration parses s as a Go duration string, falling back to
+// interpreting a bare integer as a number of seconds.
+func ParseDuration(s string) (Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	if secs, err := strconv.Atoi(s); err == nil {
+		return Duration(time.Duration(secs) * time.Second), nil
+	}
+	dur, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("config: invalid duration %q: %w", s, err)
+	}
+	return Duration(dur), nil
+}
+
+// UnmarshalXML implements xml.Unmarshaler.
+func (d *Duration) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	var s string
+	if err := dec.DecodeElement(&s, &start); err != nil {
+		return err
+	}
+	parsed, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler (gopkg.in/yaml.v3).
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	var s string
+	if err := value.Decode(&s); err != nil {
+		var n int
+		if err := value.Decode(&n); err != nil {
+			return err
+		}
+		*d = Duration(time.Duration(n) * time.Second)
+		return nil
+	}
+	parsed, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		parsed, err := ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		*d = parsed
+		return nil
+	}
+
+	var n int
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*d = Duration(time.Duration(n) * time.Second)
+	return nil
+}
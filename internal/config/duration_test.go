@@ -0,0 +1,39 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    Duration
+		wantErr bool
+	}{
+		{in: "", want: 0},
+		{in: "30s", want: Duration(30 * time.Second)},
+		{in: "5m", want: Duration(5 * time.Minute)},
+		{in: "1h30m", want: Duration(90 * time.Minute)},
+		{in: "45", want: Duration(45 * time.Second)},
+		{in: "0", want: 0},
+		{in: "not-a-duration", wantErr: true},
+	}
+
+	for _, c := range cases {
+		got, err := ParseDuration(c.in)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("ParseDuration(%q) = %v, want error", c.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDuration(%q) returned error: %v", c.in, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", c.in, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format unmarshals raw config bytes into cfg. Adding a new file format to
+// the loader only requires a new Format implementation plus a case in
+// FormatForPath.
+type Format interface {
+	Unmarshal(data []byte, cfg *APIConfig) error
+}
+
+type xmlFormat struct{}
+
+func (xmlFormat) Unmarshal(data []byte, cfg *APIConfig) error {
+	return xml.Unmarshal(data, cfg)
+}
+
+type yamlFormat struct{}
+
+func (yamlFormat) Unmarshal(data []byte, cfg *APIConfig) error {
+	return yaml.Unmarshal(data, cfg)
+}
+
+type jsonFormat struct{}
+
+func (jsonFormat) Unmarshal(data []byte, cfg *APIConfig) error {
+	return json.Unmarshal(data, cfg)
+}
+
+// FormatForPath picks a Format from path's extension: .yaml/.yml and .json
+// are recognized, anything else (including .xml) falls back to XML for
+// backward compatibility with config.xml.
+func FormatForPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return yamlFormat{}
+	case ".json":
+		return jsonFormat{}
+	default:
+		return xmlFormat{}
+	}
+}
@@ -0,0 +1,223 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// Loader builds an APIConfig by merging, in increasing order of
+// precedence:
+//
+//  1. built-in zero-value defaults
+//  2. DefaultsPath, a config.default.xml shipped alongside the binary
+//  3. ConfigPath, or inline XML from $CONFIG_XML if that is set
+//  4. .env / process environment
+//  5. command-line flags
+//
+// This mirrors the layered configor-style pattern used by mature Go
+// services: each layer only overrides the fields it actually sets.
+type Loader struct {
+	// ConfigPath is the operator-supplied config file. Its format is
+	// picked by FormatForPath from the extension.
+	ConfigPath string
+	// DefaultsPath is an optional lower-precedence file with the same
+	// shape, used to ship sane defaults alongside the binary.
+	DefaultsPath string
+	// Flags, if set, is parsed instead of a fresh FlagSet. Mainly for
+	// tests that want to control the flag set's lifetime.
+	Flags *flag.FlagSet
+	// Args overrides the arguments parsed into Flags, defaulting to
+	// os.Args[1:].
+	Args []string
+}
+
+// NewLoader returns a Loader configured with the package's conventional
+// file names: config.default.xml layered under config.xml (or
+// $CONFIG_XML_PATH).
+func NewLoader() *Loader {
+	path := os.Getenv("CONFIG_XML_PATH")
+	if path == "" {
+		path = "config.xml"
+	}
+	return &Loader{
+		ConfigPath:   path,
+		DefaultsPath: "config.default.xml",
+	}
+}
+
+// Load runs the full layered merge, interpolates $ENV_ placeholders,
+// resolves secret-typed fields, and validates the result.
+func (l *Loader) Load() (*APIConfig, error) {
+	cfg := &APIConfig{}
+
+	if data, err := os.ReadFile(l.DefaultsPath); err == nil {
+		defaults := &APIConfig{}
+		if err := FormatForPath(l.DefaultsPath).Unmarshal(data, defaults); err != nil {
+			return nil, fmt.Errorf("config: parsing defaults %s: %w", l.DefaultsPath, err)
+		}
+		mergeConfig(cfg, defaults)
+	}
+
+	if err := l.mergeConfigFile(cfg); err != nil {
+		return nil, err
+	}
+
+	_ = godotenv.Load() // Load .env file if present
+	mergeEnv(cfg)
+
+	if err := l.mergeFlags(cfg); err != nil {
+		return nil, err
+	}
+
+	interpolateEnv(cfg)
+	migrateLegacyProviders(cfg)
+	applyDefaults(cfg)
+	if err := resolveSecrets(cfg); err != nil {
+		return nil, err
+	}
+	cfg.buildDBIndex()
+	cfg.buildProviderIndex()
+
+	if len(cfg.ThirdParty.Providers) == 0 {
+		log.Printf("config: no THIRD_PARTY PROVIDER entries configured; AI-backed endpoints will have no backend to call")
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// mergeConfigFile layers ConfigPath (or inline $CONFIG_XML) over cfg. A
+// missing ConfigPath is not an error: the defaults layer plus env/flags may
+// be enough on their own.
+func (l *Loader) mergeConfigFile(cfg *APIConfig) error {
+	layer := &APIConfig{}
+
+	if inline := os.Getenv("CONFIG_XML"); inline != "" {
+		if err := (xmlFormat{}).Unmarshal([]byte(inline), layer); err != nil {
+			return fmt.Errorf("config: parsing $CONFIG_XML: %w", err)
+		}
+		mergeConfig(cfg, layer)
+		return nil
+	}
+
+	data, err := os.ReadFile(l.ConfigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("config: reading %s: %w", l.ConfigPath, err)
+	}
+	if err := FormatForPath(l.ConfigPath).Unmarshal(data, layer); err != nil {
+		return fmt.Errorf("config: parsing %s: %w", l.ConfigPath, err)
+	}
+	mergeConfig(cfg, layer)
+	return nil
+}
+
+// mergeEnv overrides the handful of fields operators most commonly need to
+// set per-environment without editing the config file.
+func mergeEnv(cfg *APIConfig) {
+	if v := os.Getenv("PORT"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.Context.Port = n
+		}
+	}
+	if v := os.Getenv("MODE"); v != "" {
+		cfg.Context.Mode = v
+	}
+	if len(cfg.DBs) == 0 {
+		return
+	}
+	if v := os.Getenv("DB_HOST"); v != "" {
+		cfg.DBs[0].Host = v
+	}
+	if v := os.Getenv("DB_PORT"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.DBs[0].Port = n
+		}
+	}
+}
+
+// mergeFlags overrides Context.Port and Context.Mode from the -port and
+// -mode command-line flags, the last and highest-precedence layer. The DB
+// host and port that mergeEnv reads have no flag.
+func (l *Loader) mergeFlags(cfg *APIConfig) error {
+	fs := l.Flags
+	if fs == nil {
+		fs = flag.NewFlagSet("inkwell", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+	}
+
+	port := fs.Int("port", cfg.Context.Port, "HTTP port to listen on")
+	mode := fs.String("mode", cfg.Context.Mode, "gin mode: release or debug")
+
+	args := l.Args
+	if args == nil {
+		args = os.Args[1:]
+	}
+	// The real binary's argv may carry flags we don't own (go test's
+	// -test.*, or flags main defines for unrelated purposes). Parse only
+	// the subset that sets -port/-mode instead of the whole argv, so a
+	// FlagSet that knows just those two doesn't reject the rest.
+	if err := fs.Parse(filterFlags(args, "port", "mode")); err != nil {
+		return fmt.Errorf("config: parsing flags: %w", err)
+	}
+
+	cfg.Context.Port = *port
+	cfg.Context.Mode = *mode
+	return nil
+}
+
+// filterFlags returns the args that set one of names, in the forms
+// flag.Parse itself accepts (-name, -name=v, -name v, and their -- forms),
+// dropping everything else. This lets a FlagSet that only knows about
+// names parse a command line that also carries flags it doesn't define,
+// without erroring on them.
+func filterFlags(args []string, names ...string) []string {
+	want := make(map[string]bool, len(names))
+	for _, n := range names {
+		want[n] = true
+	}
+
+	var out []string
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := parseFlagToken(args[i])
+		if !want[name] {
+			continue
+		}
+		if hasValue {
+			out = append(out, "-"+name+"="+value)
+			continue
+		}
+		if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			out = append(out, "-"+name+"="+args[i+1])
+			i++
+			continue
+		}
+		out = append(out, "-"+name)
+	}
+	return out
+}
+
+// parseFlagToken splits a "-name", "--name", "-name=value", or
+// "--name=value" token into its name and, if present, value. It returns an
+// empty name for anything that isn't a flag token.
+func parseFlagToken(arg string) (name, value string, hasValue bool) {
+	if !strings.HasPrefix(arg, "-") {
+		return "", "", false
+	}
+	arg = strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+	if eq := strings.IndexByte(arg, '='); eq >= 0 {
+		return arg[:eq], arg[eq+1:], true
+	}
+	return arg, "", false
+}
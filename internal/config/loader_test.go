@@ -0,0 +1,31 @@
+package config
+
+import "testing"
+
+func TestMergeFlagsIgnoresUnrelatedFlags(t *testing.T) {
+	cfg := &APIConfig{}
+	l := &Loader{Args: []string{"-unrelated-flag=1", "-test.v=true", "-port=9090"}}
+
+	if err := l.mergeFlags(cfg); err != nil {
+		t.Fatalf("mergeFlags returned an error for a command line with flags it doesn't own: %v", err)
+	}
+	if cfg.Context.Port != 9090 {
+		t.Errorf("Context.Port = %d, want 9090", cfg.Context.Port)
+	}
+}
+
+func TestFilterFlags(t *testing.T) {
+	args := []string{"-unrelated-flag=1", "--mode", "debug", "positional", "-port=9090"}
+
+	got := filterFlags(args, "port", "mode")
+	want := []string{"-mode=debug", "-port=9090"}
+
+	if len(got) != len(want) {
+		t.Fatalf("filterFlags(%v) = %v, want %v", args, got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("filterFlags(%v)[%d] = %q, want %q", args, i, got[i], want[i])
+		}
+	}
+}
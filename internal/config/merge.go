@@ -0,0 +1,71 @@
+package config
+
+import "reflect"
+
+// mergeConfig layers src over dst: scalar fields that are non-zero in src
+// overwrite dst, nested structs merge recursively, and plain slices are
+// replaced wholesale when src sets one. DBs and ThirdParty.Providers are
+// special-cased: entries are matched by Name and merged field-by-field
+// instead of being concatenated, so a later layer can override e.g. just
+// HOST on a named DB without discarding the POOL settings an earlier layer
+// (such as config.default.xml) gave that same DB.
+func mergeConfig(dst, src *APIConfig) {
+	mergeStruct(reflect.ValueOf(dst).Elem(), reflect.ValueOf(src).Elem())
+	dst.DBs = mergeNamed(dst.DBs, src.DBs, func(d DBConfig) string { return d.Name })
+	dst.ThirdParty.Providers = mergeNamed(dst.ThirdParty.Providers, src.ThirdParty.Providers, func(p ProviderConfig) string { return p.Name })
+}
+
+// mergeNamed merges src into dst by matching on the key each entry
+// produces: entries present in both are merged field-by-field (src wins on
+// whatever it sets), entries only in src are appended.
+func mergeNamed[T any](dst, src []T, key func(T) string) []T {
+	index := make(map[string]int, len(dst))
+	for i, d := range dst {
+		index[key(d)] = i
+	}
+
+	for _, s := range src {
+		if i, ok := index[key(s)]; ok {
+			d := dst[i]
+			mergeStruct(reflect.ValueOf(&d).Elem(), reflect.ValueOf(&s).Elem())
+			dst[i] = d
+			continue
+		}
+		index[key(s)] = len(dst)
+		dst = append(dst, s)
+	}
+	return dst
+}
+
+// mergeStruct recursively copies src's non-zero fields onto dst. DBs and
+// Providers are skipped here: mergeConfig merges them separately by name
+// rather than wholesale, since they're slices of named entries, not plain
+// lists.
+func mergeStruct(dst, src reflect.Value) {
+	t := dst.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Name == "DBs" || field.Name == "Providers" {
+			continue
+		}
+
+		df := dst.Field(i)
+		sf := src.Field(i)
+		if !df.CanSet() {
+			continue
+		}
+
+		switch {
+		case df.Kind() == reflect.Struct && df.Type() != durationType:
+			mergeStruct(df, sf)
+		case df.Kind() == reflect.Slice:
+			if sf.Len() > 0 {
+				df.Set(sf)
+			}
+		default:
+			if !sf.IsZero() {
+				df.Set(sf)
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import "testing"
+
+func TestMergeConfigMergesNamedDBsByField(t *testing.T) {
+	dst := &APIConfig{
+		DBs: []DBConfig{
+			{
+				Name: "inkwell",
+				Host: "defaults-host",
+				Pool: DBPoolConfig{MaxOpenConns: 100},
+			},
+		},
+	}
+	src := &APIConfig{
+		DBs: []DBConfig{
+			{Name: "inkwell", Host: "override-host"},
+		},
+	}
+
+	mergeConfig(dst, src)
+
+	if len(dst.DBs) != 1 {
+		t.Fatalf("got %d DBs, want 1 (same-named entries should merge, not append): %+v", len(dst.DBs), dst.DBs)
+	}
+	db := dst.DBs[0]
+	if db.Host != "override-host" {
+		t.Errorf("Host = %q, want %q", db.Host, "override-host")
+	}
+	if db.Pool.MaxOpenConns != 100 {
+		t.Errorf("Pool.MaxOpenConns = %d, want 100 (should survive from the defaults layer)", db.Pool.MaxOpenConns)
+	}
+}
+
+func TestMergeConfigMergesNamedProvidersByField(t *testing.T) {
+	dst := &APIConfig{
+		ThirdParty: ThirdPartyConfig{
+			Providers: []ProviderConfig{
+				{Name: "ollama", Kind: "ollama", Model: "llama3"},
+			},
+		},
+	}
+	src := &APIConfig{
+		ThirdParty: ThirdPartyConfig{
+			Providers: []ProviderConfig{
+				{Name: "ollama", Endpoint: "http://localhost:11434"},
+			},
+		},
+	}
+
+	mergeConfig(dst, src)
+
+	if len(dst.ThirdParty.Providers) != 1 {
+		t.Fatalf("got %d providers, want 1: %+v", len(dst.ThirdParty.Providers), dst.ThirdParty.Providers)
+	}
+	p := dst.ThirdParty.Providers[0]
+	if p.Endpoint != "http://localhost:11434" {
+		t.Errorf("Endpoint = %q, want override", p.Endpoint)
+	}
+	if p.Model != "llama3" {
+		t.Errorf("Model = %q, want %q (should survive from the first layer)", p.Model, "llama3")
+	}
+}
+
+func TestMergeConfigAppendsNewNamedEntries(t *testing.T) {
+	dst := &APIConfig{DBs: []DBConfig{{Name: "inkwell"}}}
+	src := &APIConfig{DBs: []DBConfig{{Name: "analytics"}}}
+
+	mergeConfig(dst, src)
+
+	if len(dst.DBs) != 2 {
+		t.Fatalf("got %d DBs, want 2: %+v", len(dst.DBs), dst.DBs)
+	}
+}
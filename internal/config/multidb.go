@@ -0,0 +1,112 @@
+package config
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// ReplicasConfig lists read-replica DSNs for a DB, so the storage layer can
+// route reads away from the primary.
+type ReplicasConfig struct {
+	DSNs []string `xml:"DSN" yaml:"DSN" json:"DSN"`
+}
+
+// buildDBIndex indexes cfg.DBs by name so DB can do an O(1) lookup. Called
+// once the DBs slice is final, after defaults and secrets are resolved.
+func (cfg *APIConfig) buildDBIndex() {
+	cfg.dbIndex = make(map[string]DBConfig, len(cfg.DBs))
+	for _, db := range cfg.DBs {
+		cfg.dbIndex[db.Name] = db
+	}
+}
+
+// DB returns the named database's config, or the zero value and false if
+// no <DB NAME="name"> entry was configured.
+func (cfg *APIConfig) DB(name string) (DBConfig, bool) {
+	db, ok := cfg.dbIndex[name]
+	return db, ok
+}
+
+// DB returns the named database's config from the currently active
+// configuration. It is a convenience wrapper around GetConfig().DB for
+// callers that don't otherwise need the whole APIConfig.
+func DB(name string) (DBConfig, bool) {
+	cfg := GetConfig()
+	if cfg == nil {
+		return DBConfig{}, false
+	}
+	return cfg.DB(name)
+}
+
+// Primary returns db itself: the primary connection it was configured
+// with.
+func (db DBConfig) Primary() DBConfig {
+	return db
+}
+
+// Replica returns a copy of db with DSN pointed at one of its configured
+// read replicas, chosen round-robin, so repeated calls fan reads out
+// across all of them. db itself is returned unchanged if it has no
+// replicas.
+func (db DBConfig) Replica() DBConfig {
+	if len(db.Replicas.DSNs) == 0 {
+		return db
+	}
+	replica := db
+	replica.DSN = db.Replicas.DSNs[nextReplicaIndex(db.Name, len(db.Replicas.DSNs))]
+	return replica
+}
+
+var (
+	replicaMu   sync.Mutex
+	replicaNext = map[string]uint64{}
+)
+
+func nextReplicaIndex(name string, n int) int {
+	replicaMu.Lock()
+	defer replicaMu.Unlock()
+	i := replicaNext[name]
+	replicaNext[name] = i + 1
+	return int(i % uint64(n))
+}
+
+// pools holds the *sql.DB opened for each named DB, registered by the
+// storage layer via RegisterPool once it has one open.
+var (
+	poolsMu sync.RWMutex
+	pools   = map[string]*sql.DB{}
+)
+
+// RegisterPool makes db available to the rest of the process as name's
+// connection pool, so subsystems that share a database (e.g. analytics and
+// content both reading "inkwell") open it once instead of each other.
+func RegisterPool(name string, db *sql.DB) {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+	pools[name] = db
+}
+
+// Pool returns the *sql.DB previously registered for name.
+func Pool(name string) (*sql.DB, bool) {
+	poolsMu.RLock()
+	defer poolsMu.RUnlock()
+	db, ok := pools[name]
+	return db, ok
+}
+
+// ClosePools closes and forgets every registered pool. Intended for
+// graceful shutdown and for tests that open pools per-run.
+func ClosePools() error {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+
+	var firstErr error
+	for name, db := range pools {
+		if err := db.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("config: closing pool %q: %w", name, err)
+		}
+		delete(pools, name)
+	}
+	return firstErr
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+	"testing"
+)
+
+var registerFakeDriverOnce sync.Once
+
+// openFakeDB returns a *sql.DB backed by a registered no-op driver, so
+// RegisterPool/Pool/ClosePools can be exercised without a real database:
+// sql.Open only needs a driver name to exist, not a live connection.
+func openFakeDB(t *testing.T) *sql.DB {
+	t.Helper()
+	registerFakeDriverOnce.Do(func() {
+		sql.Register("config-test-fake", fakeDriver{})
+	})
+	db, err := sql.Open("config-test-fake", "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestBuildDBIndexAndLookup(t *testing.T) {
+	cfg := &APIConfig{DBs: []DBConfig{
+		{Name: "inkwell", Host: "db1"},
+		{Name: "analytics", Host: "db2"},
+	}}
+	cfg.buildDBIndex()
+
+	db, ok := cfg.DB("analytics")
+	if !ok {
+		t.Fatal("DB(\"analytics\") not found")
+	}
+	if db.Host != "db2" {
+		t.Errorf("Host = %q, want %q", db.Host, "db2")
+	}
+
+	if _, ok := cfg.DB("missing"); ok {
+		t.Error("DB(\"missing\") should report !ok")
+	}
+}
+
+func TestDBPrimaryReturnsItself(t *testing.T) {
+	db := DBConfig{Name: "inkwell", DSN: "postgres://primary"}
+	if p := db.Primary(); p.DSN != db.DSN {
+		t.Errorf("Primary().DSN = %q, want %q", p.DSN, db.DSN)
+	}
+}
+
+func TestDBReplicaRoundRobinsAcrossDSNs(t *testing.T) {
+	db := DBConfig{
+		Name: "replica-test-db",
+		DSN:  "postgres://primary",
+		Replicas: ReplicasConfig{DSNs: []string{
+			"postgres://replica-a",
+			"postgres://replica-b",
+		}},
+	}
+
+	seen := map[string]bool{}
+	for i := 0; i < 4; i++ {
+		seen[db.Replica().DSN] = true
+	}
+	if !seen["postgres://replica-a"] || !seen["postgres://replica-b"] {
+		t.Errorf("Replica() did not rotate across both configured DSNs: %v", seen)
+	}
+}
+
+func TestDBReplicaWithNoReplicasReturnsSelf(t *testing.T) {
+	db := DBConfig{Name: "no-replicas", DSN: "postgres://primary"}
+	if r := db.Replica(); r.DSN != db.DSN {
+		t.Errorf("Replica() with no replicas configured = %q, want unchanged %q", r.DSN, db.DSN)
+	}
+}
+
+// fakeDriver lets RegisterPool/Pool/ClosePools be tested against a real
+// *sql.DB without a real database: database/sql only needs a registered
+// driver name to open one, even if Open itself errors.
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) { return nil, driver.ErrBadConn }
+
+func TestRegisterPoolAndClosePools(t *testing.T) {
+	db := openFakeDB(t)
+
+	RegisterPool("pool-test-db", db)
+	t.Cleanup(func() { ClosePools() })
+
+	got, ok := Pool("pool-test-db")
+	if !ok || got != db {
+		t.Fatal("Pool did not return the *sql.DB registered under the same name")
+	}
+
+	if err := ClosePools(); err != nil {
+		t.Fatalf("ClosePools: %v", err)
+	}
+	if _, ok := Pool("pool-test-db"); ok {
+		t.Error("Pool should report !ok after ClosePools forgot it")
+	}
+}
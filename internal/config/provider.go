@@ -0,0 +1,144 @@
+package config
+
+import (
+	"encoding/xml"
+	"log"
+)
+
+// ProviderConfig describes one LLM backend the AI-serving code can call.
+type ProviderConfig struct {
+	Name     string          `xml:"NAME,attr" yaml:"NAME" json:"NAME"`
+	Kind     string          `xml:"KIND,attr" yaml:"KIND" json:"KIND"` // "ollama", "hf", "openai", "anthropic", "vllm"
+	Default  bool            `xml:"DEFAULT,attr" yaml:"DEFAULT" json:"DEFAULT"`
+	Endpoint string          `xml:"ENDPOINT" yaml:"ENDPOINT" json:"ENDPOINT"`
+	APIKey   DBPassword      `xml:"API_KEY" yaml:"API_KEY" json:"API_KEY"`
+	Model    string          `xml:"MODEL" yaml:"MODEL" json:"MODEL"`
+	Timeout  Duration        `xml:"TIMEOUT" yaml:"TIMEOUT" json:"TIMEOUT" default:"30s"`
+	Options  ProviderOptions `xml:"OPTIONS" yaml:"OPTIONS" json:"OPTIONS"`
+}
+
+// ProviderOptions is a set of per-provider key/value settings. In XML it is
+// represented as repeated <OPTION KEY="...">value</OPTION> children, since
+// encoding/xml has no native map support; YAML and JSON use a plain map.
+type ProviderOptions map[string]string
+
+// UnmarshalXML implements xml.Unmarshaler.
+func (o *ProviderOptions) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	opts := ProviderOptions{}
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			var key string
+			for _, attr := range t.Attr {
+				if attr.Name.Local == "KEY" {
+					key = attr.Value
+				}
+			}
+			var value string
+			if err := dec.DecodeElement(&value, &t); err != nil {
+				return err
+			}
+			if key != "" {
+				opts[key] = value
+			}
+		case xml.EndElement:
+			if t.Name == start.Name {
+				*o = opts
+				return nil
+			}
+		}
+	}
+}
+
+// migrateLegacyProviders synthesizes PROVIDER entries from the deprecated
+// THIRD_PARTY.HF_TOKEN/OLLAMA_HOST fields when no PROVIDER entries were
+// configured, so a config file written before those fields were replaced
+// still ends up with a working AI backend instead of silently losing one.
+// Only fills in when Providers is empty: once an operator has migrated to
+// PROVIDER entries, the legacy fields are ignored.
+func migrateLegacyProviders(cfg *APIConfig) {
+	if len(cfg.ThirdParty.Providers) > 0 {
+		return
+	}
+
+	if cfg.ThirdParty.OllamaHost != "" {
+		cfg.ThirdParty.Providers = append(cfg.ThirdParty.Providers, ProviderConfig{
+			Name:     "ollama",
+			Kind:     "ollama",
+			Default:  true,
+			Endpoint: cfg.ThirdParty.OllamaHost,
+		})
+	}
+	if cfg.ThirdParty.HFToken != "" {
+		cfg.ThirdParty.Providers = append(cfg.ThirdParty.Providers, ProviderConfig{
+			Name:    "hf",
+			Kind:    "hf",
+			Default: cfg.ThirdParty.OllamaHost == "",
+			APIKey:  DBPassword{Value: cfg.ThirdParty.HFToken},
+		})
+	}
+
+	if len(cfg.ThirdParty.Providers) > 0 {
+		log.Printf("config: THIRD_PARTY.HF_TOKEN/OLLAMA_HOST are deprecated, migrated to %d PROVIDER entr(ies); set PROVIDER directly instead", len(cfg.ThirdParty.Providers))
+	}
+}
+
+// buildProviderIndex indexes ThirdParty.Providers by name. Called once the
+// providers are final, after defaults and secrets are resolved.
+func (cfg *APIConfig) buildProviderIndex() {
+	cfg.providerIndex = make(map[string]ProviderConfig, len(cfg.ThirdParty.Providers))
+	for _, p := range cfg.ThirdParty.Providers {
+		cfg.providerIndex[p.Name] = p
+	}
+}
+
+// Provider returns the named provider's config, or the zero value and
+// false if no <PROVIDER NAME="name"> entry was configured.
+func (cfg *APIConfig) Provider(name string) (ProviderConfig, bool) {
+	p, ok := cfg.providerIndex[name]
+	return p, ok
+}
+
+// Provider looks up name in the currently active configuration. It is a
+// convenience wrapper around GetConfig().Provider.
+func Provider(name string) (ProviderConfig, bool) {
+	cfg := GetConfig()
+	if cfg == nil {
+		return ProviderConfig{}, false
+	}
+	return cfg.Provider(name)
+}
+
+// DefaultProvider returns the provider configured with DEFAULT="true" for
+// kind (e.g. "ollama"), or the first provider of that kind if none is
+// marked default.
+func (cfg *APIConfig) DefaultProvider(kind string) (ProviderConfig, bool) {
+	var firstMatch ProviderConfig
+	found := false
+	for _, p := range cfg.ThirdParty.Providers {
+		if p.Kind != kind {
+			continue
+		}
+		if p.Default {
+			return p, true
+		}
+		if !found {
+			firstMatch, found = p, true
+		}
+	}
+	return firstMatch, found
+}
+
+// DefaultProvider looks up kind in the currently active configuration. It
+// is a convenience wrapper around GetConfig().DefaultProvider.
+func DefaultProvider(kind string) (ProviderConfig, bool) {
+	cfg := GetConfig()
+	if cfg == nil {
+		return ProviderConfig{}, false
+	}
+	return cfg.DefaultProvider(kind)
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestBuildProviderIndexAndLookup(t *testing.T) {
+	cfg := &APIConfig{ThirdParty: ThirdPartyConfig{Providers: []ProviderConfig{
+		{Name: "ollama", Kind: "ollama"},
+		{Name: "openai", Kind: "openai"},
+	}}}
+	cfg.buildProviderIndex()
+
+	p, ok := cfg.Provider("openai")
+	if !ok {
+		t.Fatal("Provider(\"openai\") not found")
+	}
+	if p.Kind != "openai" {
+		t.Errorf("Kind = %q, want %q", p.Kind, "openai")
+	}
+
+	if _, ok := cfg.Provider("missing"); ok {
+		t.Error("Provider(\"missing\") should report !ok")
+	}
+}
+
+func TestDefaultProviderPrefersDefaultFlag(t *testing.T) {
+	cfg := &APIConfig{ThirdParty: ThirdPartyConfig{Providers: []ProviderConfig{
+		{Name: "ollama-a", Kind: "ollama"},
+		{Name: "ollama-b", Kind: "ollama", Default: true},
+		{Name: "ollama-c", Kind: "ollama"},
+	}}}
+
+	p, ok := cfg.DefaultProvider("ollama")
+	if !ok {
+		t.Fatal("DefaultProvider(\"ollama\") not found")
+	}
+	if p.Name != "ollama-b" {
+		t.Errorf("Name = %q, want %q (the one with DEFAULT=true)", p.Name, "ollama-b")
+	}
+}
+
+func TestDefaultProviderFallsBackToFirstMatch(t *testing.T) {
+	cfg := &APIConfig{ThirdParty: ThirdPartyConfig{Providers: []ProviderConfig{
+		{Name: "ollama-a", Kind: "ollama"},
+		{Name: "ollama-b", Kind: "ollama"},
+	}}}
+
+	p, ok := cfg.DefaultProvider("ollama")
+	if !ok {
+		t.Fatal("DefaultProvider(\"ollama\") not found")
+	}
+	if p.Name != "ollama-a" {
+		t.Errorf("Name = %q, want %q (first match, none marked default)", p.Name, "ollama-a")
+	}
+}
+
+func TestDefaultProviderNoMatch(t *testing.T) {
+	cfg := &APIConfig{ThirdParty: ThirdPartyConfig{Providers: []ProviderConfig{
+		{Name: "openai", Kind: "openai"},
+	}}}
+
+	if _, ok := cfg.DefaultProvider("ollama"); ok {
+		t.Error("DefaultProvider(\"ollama\") should report !ok when no provider of that kind exists")
+	}
+}
+
+func TestMigrateLegacyProvidersSynthesizesFromOldFields(t *testing.T) {
+	cfg := &APIConfig{ThirdParty: ThirdPartyConfig{
+		OllamaHost: "http://localhost:11434",
+		HFToken:    "hf_abc123",
+	}}
+
+	migrateLegacyProviders(cfg)
+
+	if len(cfg.ThirdParty.Providers) != 2 {
+		t.Fatalf("got %d providers, want 2: %+v", len(cfg.ThirdParty.Providers), cfg.ThirdParty.Providers)
+	}
+
+	cfg.buildProviderIndex()
+	ollama, ok := cfg.Provider("ollama")
+	if !ok || ollama.Endpoint != "http://localhost:11434" || !ollama.Default {
+		t.Errorf("synthesized ollama provider = %+v, ok=%v", ollama, ok)
+	}
+
+	hf, ok := cfg.Provider("hf")
+	if !ok || hf.APIKey.Value != "hf_abc123" {
+		t.Errorf("synthesized hf provider = %+v, ok=%v", hf, ok)
+	}
+}
+
+func TestMigrateLegacyProvidersSkippedWhenProvidersAlreadySet(t *testing.T) {
+	cfg := &APIConfig{ThirdParty: ThirdPartyConfig{
+		OllamaHost: "http://localhost:11434",
+		Providers:  []ProviderConfig{{Name: "openai", Kind: "openai"}},
+	}}
+
+	migrateLegacyProviders(cfg)
+
+	if len(cfg.ThirdParty.Providers) != 1 {
+		t.Fatalf("got %d providers, want 1 (legacy fields should be ignored once PROVIDER entries exist): %+v", len(cfg.ThirdParty.Providers), cfg.ThirdParty.Providers)
+	}
+}
+
+func TestMigrateLegacyProvidersNoopWhenNeitherFieldSet(t *testing.T) {
+	cfg := &APIConfig{}
+
+	migrateLegacyProviders(cfg)
+
+	if len(cfg.ThirdParty.Providers) != 0 {
+		t.Fatalf("got %d providers, want 0", len(cfg.ThirdParty.Providers))
+	}
+}
+
+func TestProviderOptionsUnmarshalXML(t *testing.T) {
+	var p ProviderConfig
+	data := `<PROVIDER NAME="ollama" KIND="ollama">
+  <OPTIONS>
+    <OPTION KEY="num_ctx">4096</OPTION>
+    <OPTION KEY="temperature">0.2</OPTION>
+  </OPTIONS>
+</PROVIDER>`
+
+	if err := xml.Unmarshal([]byte(data), &p); err != nil {
+		t.Fatalf("xml.Unmarshal: %v", err)
+	}
+
+	if p.Options["num_ctx"] != "4096" {
+		t.Errorf("Options[\"num_ctx\"] = %q, want %q", p.Options["num_ctx"], "4096")
+	}
+	if p.Options["temperature"] != "0.2" {
+		t.Errorf("Options[\"temperature\"] = %q, want %q", p.Options["temperature"], "0.2")
+	}
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// SecretResolver resolves a reference string (an env var name, a file path,
+// a Vault KV path, ...) into the secret it points at. Resolvers are
+// registered by the "TYPE" attribute they handle, e.g. TYPE="vault".
+type SecretResolver interface {
+	Resolve(ref string) (string, error)
+}
+
+var (
+	resolversMu sync.RWMutex
+	resolvers   = map[string]SecretResolver{}
+)
+
+// RegisterSecretResolver makes r available for DBPassword.Type (and any
+// other secret-typed field) values of "type". Registering under an
+// already-registered type replaces it; this is normally done from an
+// init() func.
+func RegisterSecretResolver(typ string, r SecretResolver) {
+	resolversMu.Lock()
+	defer resolversMu.Unlock()
+	resolvers[typ] = r
+}
+
+func secretResolver(typ string) (SecretResolver, bool) {
+	resolversMu.RLock()
+	defer resolversMu.RUnlock()
+	r, ok := resolvers[typ]
+	return r, ok
+}
+
+func init() {
+	RegisterSecretResolver("env", envSecretResolver{})
+	RegisterSecretResolver("file", fileSecretResolver{})
+	RegisterSecretResolver("vault", vaultSecretResolver{})
+}
+
+// envSecretResolver reads the named environment variable.
+type envSecretResolver struct{}
+
+func (envSecretResolver) Resolve(ref string) (string, error) {
+	v, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("config: env var %q is not set", ref)
+	}
+	return v, nil
+}
+
+// fileSecretResolver reads the trimmed contents of the given file path.
+type fileSecretResolver struct{}
+
+func (fileSecretResolver) Resolve(ref string) (string, error) {
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return "", fmt.Errorf("config: reading secret file %q: %w", ref, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// vaultSecretResolver fetches a secret from a HashiCorp Vault KV path using
+// the address and token found in VAULT_ADDR / VAULT_TOKEN. ref is the KV
+// path plus the field name, e.g. "secret/data/inkwell/db#password".
+type vaultSecretResolver struct{}
+
+func (vaultSecretResolver) Resolve(ref string) (string, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok {
+		return "", fmt.Errorf("config: vault ref %q must be \"path#field\"", ref)
+	}
+	return readVaultField(path, field)
+}
+
+// resolveSecrets fills in any DB password that is not stored as plain text
+// by looking it up through the resolver registered for Password.Type.
+// TYPE="plain" (the default) is left untouched.
+func resolveSecrets(cfg *APIConfig) error {
+	for i := range cfg.DBs {
+		if err := resolvePassword(&cfg.DBs[i].Password); err != nil {
+			return fmt.Errorf("config: DB %q: %w", cfg.DBs[i].Name, err)
+		}
+	}
+	for i := range cfg.ThirdParty.Providers {
+		if err := resolvePassword(&cfg.ThirdParty.Providers[i].APIKey); err != nil {
+			return fmt.Errorf("config: provider %q: %w", cfg.ThirdParty.Providers[i].Name, err)
+		}
+	}
+	return nil
+}
+
+func resolvePassword(p *DBPassword) error {
+	typ := p.Type
+	if typ == "" || typ == "plain" {
+		return nil
+	}
+
+	resolver, ok := secretResolver(typ)
+	if !ok {
+		return fmt.Errorf("config: no secret resolver registered for TYPE=%q", typ)
+	}
+
+	resolved, err := resolver.Resolve(p.Value)
+	if err != nil {
+		return err
+	}
+	p.Value = resolved
+	return nil
+}
+
+// interpolateEnv walks cfg and replaces any string field whose value looks
+// like "$ENV_FOO" with the value of the FOO environment variable, so XML
+// files can reference env vars without a TYPE attribute (e.g. inside
+// <HOST>$ENV_DB_HOST</HOST>).
+func interpolateEnv(cfg *APIConfig) {
+	interpolateValue(reflect.ValueOf(cfg).Elem())
+}
+
+func interpolateValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			interpolateValue(v.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			interpolateValue(v.Index(i))
+		}
+	case reflect.String:
+		if v.CanSet() {
+			if resolved, ok := expandEnvPlaceholder(v.String()); ok {
+				v.SetString(resolved)
+			}
+		}
+	}
+}
+
+// expandEnvPlaceholder returns the value of FOO when s is exactly
+// "$ENV_FOO". It does not do shell-style interpolation mid-string.
+func expandEnvPlaceholder(s string) (string, bool) {
+	const prefix = "$ENV_"
+	if !strings.HasPrefix(s, prefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(s, prefix)
+	if name == "" {
+		return "", false
+	}
+	return os.Getenv(name), true
+}
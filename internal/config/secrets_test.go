@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolvePasswordEnv(t *testing.T) {
+	t.Setenv("TEST_DB_PASSWORD", "s3cret")
+
+	p := &DBPassword{Type: "env", Value: "TEST_DB_PASSWORD"}
+	if err := resolvePassword(p); err != nil {
+		t.Fatalf("resolvePassword: %v", err)
+	}
+	if p.Value != "s3cret" {
+		t.Errorf("Value = %q, want %q", p.Value, "s3cret")
+	}
+}
+
+func TestResolvePasswordFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(path, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	p := &DBPassword{Type: "file", Value: path}
+	if err := resolvePassword(p); err != nil {
+		t.Fatalf("resolvePassword: %v", err)
+	}
+	if p.Value != "s3cret" {
+		t.Errorf("Value = %q, want %q", p.Value, "s3cret")
+	}
+}
+
+func TestResolvePasswordPlainLeftAlone(t *testing.T) {
+	p := &DBPassword{Type: "plain", Value: "unchanged"}
+	if err := resolvePassword(p); err != nil {
+		t.Fatalf("resolvePassword: %v", err)
+	}
+	if p.Value != "unchanged" {
+		t.Errorf("Value = %q, want unchanged", p.Value)
+	}
+}
+
+func TestResolvePasswordUnknownType(t *testing.T) {
+	p := &DBPassword{Type: "made-up", Value: "x"}
+	if err := resolvePassword(p); err == nil {
+		t.Error("resolvePassword with an unregistered TYPE should return an error, got nil")
+	}
+}
+
+func TestExpandEnvPlaceholder(t *testing.T) {
+	t.Setenv("TEST_HOST", "db.prod.internal")
+
+	got, ok := expandEnvPlaceholder("$ENV_TEST_HOST")
+	if !ok {
+		t.Fatal("expandEnvPlaceholder did not recognize $ENV_ prefix")
+	}
+	if got != "db.prod.internal" {
+		t.Errorf("got %q, want %q", got, "db.prod.internal")
+	}
+
+	if _, ok := expandEnvPlaceholder("plain-value"); ok {
+		t.Error("expandEnvPlaceholder should not match a string without the $ENV_ prefix")
+	}
+}
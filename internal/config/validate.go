@@ -0,0 +1,63 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// knownDrivers lists the DB.Driver values the storage layer supports.
+var knownDrivers = map[string]bool{
+	"postgres": true,
+	"mysql":    true,
+	"sqlite":   true,
+}
+
+// ValidationError aggregates every problem Validate finds so an operator
+// can fix a config in one pass instead of one field at a time.
+type ValidationError struct {
+	Errors []error
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("config: %d validation error(s): %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap lets errors.Is/As see through a ValidationError to its members.
+func (e *ValidationError) Unwrap() []error {
+	return e.Errors
+}
+
+// Validate checks that cfg has everything the rest of the service needs to
+// start. It returns a *ValidationError listing every problem found, rather
+// than stopping at the first one.
+func (cfg *APIConfig) Validate() error {
+	var errs []error
+
+	if cfg.Context.Port <= 0 {
+		errs = append(errs, errors.New("CONTEXT.PORT must be > 0"))
+	}
+	for _, db := range cfg.DBs {
+		if !knownDrivers[db.Driver] {
+			errs = append(errs, fmt.Errorf("DB %q: DRIVER %q is not a known driver", db.Name, db.Driver))
+		}
+	}
+	for _, p := range cfg.ThirdParty.Providers {
+		if p.Endpoint == "" {
+			continue
+		}
+		if u, err := url.Parse(p.Endpoint); err != nil || u.Host == "" {
+			errs = append(errs, fmt.Errorf("PROVIDER %q: ENDPOINT %q is not a reachable URL", p.Name, p.Endpoint))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: errs}
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateRejectsMissingDriver(t *testing.T) {
+	cfg := &APIConfig{Context: ContextConfig{Port: 8080}, DBs: []DBConfig{{Name: "inkwell"}}}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate should reject a DB with no DRIVER set, got nil")
+	}
+}
+
+func TestValidateRejectsUnknownDriver(t *testing.T) {
+	cfg := &APIConfig{Context: ContextConfig{Port: 8080}, DBs: []DBConfig{{Name: "inkwell", Driver: "made-up"}}}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate should reject an unknown DRIVER, got nil")
+	}
+}
+
+func TestValidateAcceptsKnownDriver(t *testing.T) {
+	cfg := &APIConfig{Context: ContextConfig{Port: 8080}, DBs: []DBConfig{{Name: "inkwell", Driver: "postgres"}}}
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate with a known driver returned an error: %v", err)
+	}
+}
+
+func TestValidateCollectsAllErrors(t *testing.T) {
+	cfg := &APIConfig{
+		Context: ContextConfig{Port: 0},
+		DBs:     []DBConfig{{Name: "inkwell", Driver: "made-up"}},
+		ThirdParty: ThirdPartyConfig{
+			Providers: []ProviderConfig{{Name: "ollama", Endpoint: "not-a-url"}},
+		},
+	}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate should return an error when every check fails")
+	}
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Validate should return a *ValidationError, got %T", err)
+	}
+	if len(verr.Errors) != 3 {
+		t.Fatalf("got %d errors, want 3 (port, driver, endpoint): %v", len(verr.Errors), verr.Errors)
+	}
+}
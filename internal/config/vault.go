@@ -0,0 +1,59 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// readVaultField fetches path from Vault's KV HTTP API and returns field
+// from the secret's data map. It talks to the address and token found in
+// VAULT_ADDR / VAULT_TOKEN; both must be set for the "vault" secret type
+// to work.
+func readVaultField(path, field string) (string, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	token := os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return "", fmt.Errorf("config: VAULT_ADDR and VAULT_TOKEN must be set to resolve vault secrets")
+	}
+
+	u, err := url.JoinPath(addr, "v1", path)
+	if err != nil {
+		return "", fmt.Errorf("config: building vault url: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return "", fmt.Errorf("config: building vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("config: calling vault at %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("config: vault returned %s for %s", resp.Status, path)
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("config: decoding vault response: %w", err)
+	}
+
+	value, ok := body.Data.Data[field]
+	if !ok {
+		return "", fmt.Errorf("config: vault secret %s has no field %q", path, field)
+	}
+	return value, nil
+}
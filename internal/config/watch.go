@@ -0,0 +1,125 @@
+package config
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Listener is notified after a reload changes the active configuration.
+// old is the configuration that was active before the reload.
+type Listener func(old, new *APIConfig)
+
+var (
+	listenersMu sync.RWMutex
+	listeners   []Listener
+)
+
+// Subscribe registers fn to be called whenever a reload swaps in a new
+// configuration. fn is not called for the initial LoadConfig.
+func Subscribe(fn Listener) {
+	listenersMu.Lock()
+	defer listenersMu.Unlock()
+	listeners = append(listeners, fn)
+}
+
+// Reload runs the same Loader LoadConfig started with end to end --
+// defaults, config file, env, and flags -- and atomically swaps in the
+// result. A bad or unloadable config leaves the currently running one
+// untouched.
+func Reload() error {
+	if activeLoader == nil {
+		return errors.New("config: Reload called before LoadConfig")
+	}
+
+	newCfg, err := activeLoader.Load()
+	if err != nil {
+		return err
+	}
+
+	old := current.Swap(newCfg)
+	notifyListeners(old, newCfg)
+	return nil
+}
+
+func notifyListeners(old, new *APIConfig) {
+	listenersMu.RLock()
+	defer listenersMu.RUnlock()
+	for _, fn := range listeners {
+		fn(old, new)
+	}
+}
+
+// startWatch watches xmlPath for writes/renames (the pattern most editors
+// and `kubectl cp`/configmap updates use) and triggers Reload on change.
+func startWatch(xmlPath string) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Printf("config: file watch disabled, could not start fsnotify: %v", err)
+		return
+	}
+
+	dir := filepath.Dir(xmlPath)
+	if err := watcher.Add(dir); err != nil {
+		log.Printf("config: file watch disabled, could not watch %s: %v", dir, err)
+		watcher.Close()
+		return
+	}
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != filepath.Clean(xmlPath) {
+					continue
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+					continue
+				}
+				if err := Reload(); err != nil {
+					log.Printf("config: reload of %s failed, keeping previous config: %v", xmlPath, err)
+				} else {
+					log.Printf("config: reloaded %s", xmlPath)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("config: fsnotify error: %v", err)
+			}
+		}
+	}()
+}
+
+// ReloadHandler returns an http.HandlerFunc for POST /admin/config/reload,
+// gated by HTTP basic auth against the given credentials.
+func ReloadHandler(user, pass string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		gotUser, gotPass, ok := r.BasicAuth()
+		if !ok || subtle.ConstantTimeCompare([]byte(gotUser), []byte(user)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(gotPass), []byte(pass)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="config"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if err := Reload(); err != nil {
+			http.Error(w, "reload failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const watchTestValidConfig = `<API>
+  <CONTEXT><PORT>8080</PORT></CONTEXT>
+  <DB NAME="inkwell"><DRIVER>postgres</DRIVER></DB>
+</API>`
+
+const watchTestValidConfigV2 = `<API>
+  <CONTEXT><PORT>9090</PORT></CONTEXT>
+  <DB NAME="inkwell"><DRIVER>postgres</DRIVER></DB>
+</API>`
+
+const watchTestInvalidConfig = `<API>
+  <CONTEXT><PORT>8080</PORT></CONTEXT>
+  <DB NAME="inkwell"><DRIVER>not-a-real-driver</DRIVER></DB>
+</API>`
+
+// withLoadedConfig loads path through a fresh Loader, points the package's
+// current/activeLoader globals at the result (standing in for what
+// LoadConfig's loadOnce.Do would have done), and restores the previous
+// globals when the test ends. It returns the Loader so the test can rewrite
+// the file and call Reload.
+func withLoadedConfig(t *testing.T, path string) *Loader {
+	t.Helper()
+
+	l := &Loader{ConfigPath: path, DefaultsPath: filepath.Join(t.TempDir(), "config.default.xml")}
+	cfg, err := l.Load()
+	if err != nil {
+		t.Fatalf("initial Load: %v", err)
+	}
+
+	prevCurrent := current.Load()
+	prevLoader := activeLoader
+	current.Store(cfg)
+	activeLoader = l
+	t.Cleanup(func() {
+		current.Store(prevCurrent)
+		activeLoader = prevLoader
+	})
+
+	return l
+}
+
+// withNoListeners clears the package-level listeners slice for the
+// duration of a test, so tests don't see callbacks left behind by others.
+func withNoListeners(t *testing.T) {
+	t.Helper()
+	prev := listeners
+	listeners = nil
+	t.Cleanup(func() { listeners = prev })
+}
+
+func writeConfigFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+}
+
+func TestReloadKeepsOldConfigOnValidationFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.xml")
+	writeConfigFile(t, path, watchTestValidConfig)
+	withLoadedConfig(t, path)
+	before := GetConfig()
+
+	writeConfigFile(t, path, watchTestInvalidConfig)
+
+	if err := Reload(); err == nil {
+		t.Fatal("Reload with an invalid config should return an error")
+	}
+	if got := GetConfig(); got != before {
+		t.Error("Reload with an invalid config should leave the previously active config in place")
+	}
+}
+
+func TestReloadNotifiesListenersWithOldAndNew(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.xml")
+	writeConfigFile(t, path, watchTestValidConfig)
+	withLoadedConfig(t, path)
+	withNoListeners(t)
+	before := GetConfig()
+
+	var calls int
+	var gotOld, gotNew *APIConfig
+	Subscribe(func(old, new *APIConfig) {
+		calls++
+		gotOld, gotNew = old, new
+	})
+
+	writeConfigFile(t, path, watchTestValidConfigV2)
+	if err := Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("listener called %d times, want 1", calls)
+	}
+	if gotOld != before {
+		t.Error("listener's old pointer should be the config that was active before Reload")
+	}
+	if gotNew != GetConfig() {
+		t.Error("listener's new pointer should be the config Reload just swapped in")
+	}
+	if gotNew.Context.Port != 9090 {
+		t.Errorf("Context.Port = %d, want 9090 (the rewritten file's value)", gotNew.Context.Port)
+	}
+}
+
+func TestListenersNotCalledForInitialLoad(t *testing.T) {
+	withNoListeners(t)
+
+	called := false
+	Subscribe(func(old, new *APIConfig) { called = true })
+
+	// Stores a config the way LoadConfig's loadOnce.Do body does on success
+	// (it also records the Loader and starts the file watch, left out here).
+	// Neither step notifies listeners; they only fire from Reload.
+	current.Store(&APIConfig{})
+
+	if called {
+		t.Error("Subscribe callback fired on the initial load; it should only fire on Reload")
+	}
+}
+
+func TestReloadHandler(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.xml")
+	writeConfigFile(t, path, watchTestValidConfig)
+	withLoadedConfig(t, path)
+
+	handler := ReloadHandler("admin", "s3cret")
+
+	t.Run("missing credentials", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/config/reload", nil)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("wrong credentials", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/config/reload", nil)
+		req.SetBasicAuth("admin", "wrong")
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("wrong method", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/config/reload", nil)
+		req.SetBasicAuth("admin", "s3cret")
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/config/reload", nil)
+		req.SetBasicAuth("admin", "s3cret")
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusNoContent)
+		}
+	})
+}